@@ -0,0 +1,20 @@
+package main
+
+import "github.com/spf13/cobra"
+
+// newChainCmd builds `cnfinder chain`, which dumps every hop of each
+// subdomain's CNAME chain. Wildcard filtering defaults off here, unlike
+// resolve/takeover, since a raw chain dump shouldn't silently drop
+// entries that happen to match the apex's wildcard baseline.
+func newChainCmd() *cobra.Command {
+	var f scanFlags
+	cmd := &cobra.Command{
+		Use:   "chain",
+		Short: "Dump the full CNAME chain (all hops) for a list of subdomains",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runScan(cmd, &f, false, nil, 0)
+		},
+	}
+	addScanFlags(cmd, &f, false)
+	return cmd
+}
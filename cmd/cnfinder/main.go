@@ -0,0 +1,37 @@
+// Command cnfinder resolves CNAME records for a list of subdomains and
+// flags ones left dangling or vulnerable to takeover.
+package main
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/OctaYus/cnfinder/internal/logger"
+)
+
+const (
+	colorCyan  = "\033[36m"
+	colorReset = "\033[0m"
+)
+
+const banner = `
+  ____       _____ _           _
+ / ___|_ __ |  ___(_)_ __   __| | ___ _ __
+| |   | '_ \| |_  | | '_ \ / _' |/ _ \ '__|
+| |___| | | |  _| | | | | | (_| |  __/ |
+ \____|_| |_|_|   |_|_| |_|\__,_|\___|_|
+
+`
+
+func main() {
+	if logger.ColorEnabled() {
+		fmt.Fprint(os.Stderr, colorCyan, banner, colorReset)
+	} else {
+		fmt.Fprint(os.Stderr, banner)
+	}
+
+	if err := rootCmd().Execute(); err != nil {
+		logger.Errorf("%v", err)
+		os.Exit(1)
+	}
+}
@@ -0,0 +1,122 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"runtime"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/OctaYus/cnfinder/internal/logger"
+	"github.com/OctaYus/cnfinder/pkg/cnfinder"
+)
+
+// newProbeCmd builds `cnfinder probe`, which skips DNS entirely and
+// re-verifies fingerprint matches from a previously generated "sub >
+// cname" mapping, e.g. the txt output of `resolve` or `chain`.
+func newProbeCmd() *cobra.Command {
+	var inputFile, outputFile, outputFormat, fingerprintsPath string
+	var timeout time.Duration
+	var workers int
+
+	cmd := &cobra.Command{
+		Use:   "probe",
+		Short: "Re-check takeover fingerprints from a previously resolved sub > cname mapping",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			pairs, err := readPairs(inputFile)
+			if err != nil {
+				return fmt.Errorf("reading input: %w", err)
+			}
+			if len(pairs) == 0 {
+				logger.Infof("No sub > cname pairs found in input, exiting.")
+				return nil
+			}
+
+			fps, err := cnfinder.LoadFingerprints(fingerprintsPath)
+			if err != nil {
+				return fmt.Errorf("loading fingerprints: %w", err)
+			}
+
+			of, err := os.Create(outputFile)
+			if err != nil {
+				return fmt.Errorf("opening output file %s: %w", outputFile, err)
+			}
+			defer of.Close()
+
+			writer, err := cnfinder.NewOutputWriter(outputFormat, of)
+			if err != nil {
+				return err
+			}
+
+			for _, res := range cnfinder.Probe(pairs, fps, timeout, workers) {
+				if err := writer.WriteRecord(res); err != nil {
+					logger.Errorf("failed writing result for %s: %v", res.Input, err)
+					continue
+				}
+				if res.Status == cnfinder.StatusTakeover {
+					logger.Errorf("%s > %s VULNERABLE (%s)", res.Input, res.Chain[0], res.Fingerprint)
+				} else {
+					logger.Infof("%s > %s", res.Input, res.Chain[0])
+				}
+			}
+			return writer.Close()
+		},
+	}
+
+	cmd.Flags().StringVarP(&inputFile, "list", "l", "", "sub > cname mapping file to read back, or '-' for stdin")
+	cmd.Flags().StringVarP(&outputFile, "output", "o", "cnames.txt", "output file")
+	cmd.Flags().StringVar(&outputFormat, "of", "txt", "output file format: txt|json|jsonl|csv")
+	cmd.Flags().StringVar(&fingerprintsPath, "fingerprints", "", "path to a custom fingerprints JSON file (default: embedded database)")
+	cmd.Flags().DurationVar(&timeout, "takeover-timeout", 7*time.Second, "HTTP timeout for takeover probes, e.g. 5s")
+	cmd.Flags().IntVarP(&workers, "threads", "t", runtime.NumCPU(), "number of concurrent workers (default: CPUs)")
+	cmd.MarkFlagRequired("list")
+	return cmd
+}
+
+// readPairs parses path's "sub > cname" lines, as written by resolve/chain's
+// txt output (any trailing " [status]" tags are ignored).
+func readPairs(path string) ([]cnfinder.Pair, error) {
+	var f *os.File
+	if path == "-" {
+		f = os.Stdin
+	} else {
+		var err error
+		f, err = os.Open(path)
+		if err != nil {
+			return nil, err
+		}
+		defer f.Close()
+	}
+
+	var pairs []cnfinder.Pair
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		if p, ok := parsePairLine(line); ok {
+			pairs = append(pairs, p)
+		}
+	}
+	return pairs, scanner.Err()
+}
+
+// parsePairLine extracts the original input and its terminal CNAME hop
+// from a "sub > hop > hop [tag]" line, discarding any bracketed tags.
+func parsePairLine(line string) (cnfinder.Pair, bool) {
+	if tag := strings.IndexByte(line, '['); tag != -1 {
+		line = strings.TrimSpace(line[:tag])
+	}
+	fields := strings.Split(line, ">")
+	for i := range fields {
+		fields[i] = strings.TrimSpace(fields[i])
+	}
+	if len(fields) < 2 || fields[0] == "" || fields[len(fields)-1] == "" {
+		return cnfinder.Pair{}, false
+	}
+	return cnfinder.Pair{Sub: fields[0], CNAME: fields[len(fields)-1]}, true
+}
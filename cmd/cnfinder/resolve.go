@@ -0,0 +1,18 @@
+package main
+
+import "github.com/spf13/cobra"
+
+// newResolveCmd builds `cnfinder resolve`, the plain CNAME-chain sweep
+// with no takeover fingerprinting: the original tool's default behavior.
+func newResolveCmd() *cobra.Command {
+	var f scanFlags
+	cmd := &cobra.Command{
+		Use:   "resolve",
+		Short: "Resolve full CNAME chains for a list of subdomains",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runScan(cmd, &f, false, nil, 0)
+		},
+	}
+	addScanFlags(cmd, &f, true)
+	return cmd
+}
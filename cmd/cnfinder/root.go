@@ -0,0 +1,263 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/OctaYus/cnfinder/internal/logger"
+	"github.com/OctaYus/cnfinder/pkg/cnfinder"
+)
+
+// scanFlags holds the flags shared by resolve, takeover and chain, which
+// all run a Scanner over a list of subdomains and differ only in their
+// defaults and which extra checks they enable.
+type scanFlags struct {
+	inputFile      string
+	outputFile     string
+	timeout        time.Duration
+	workers        int
+	appendMode     bool
+	resolverList   string
+	resolverFile   string
+	qps            float64
+	retries        int
+	maxHops        int
+	wildcardFilter bool
+	outputFormat   string
+	streamStdout   bool
+}
+
+// addScanFlags registers the flags common to resolve/takeover/chain on
+// cmd, defaulting wildcardFilter to wildcardDefault so chain (which wants
+// a raw, unfiltered dump) can opt out while resolve and takeover opt in.
+func addScanFlags(cmd *cobra.Command, f *scanFlags, wildcardDefault bool) {
+	cmd.Flags().StringVarP(&f.inputFile, "list", "l", "", "input file with one subdomain per line, or '-' for stdin")
+	cmd.Flags().StringVarP(&f.outputFile, "output", "o", "cnames.txt", "output file")
+	cmd.Flags().DurationVar(&f.timeout, "timeout", 5*time.Second, "DNS query timeout, e.g. 3s, 500ms")
+	cmd.Flags().IntVarP(&f.workers, "threads", "t", runtime.NumCPU(), "number of concurrent workers (default: CPUs)")
+	cmd.Flags().BoolVarP(&f.appendMode, "append", "a", false, "append to output instead of truncating")
+	cmd.Flags().StringVarP(&f.resolverList, "resolvers", "r", "", "comma-separated upstream DNS servers to use instead of the system resolver, e.g. 1.1.1.1,8.8.8.8")
+	cmd.Flags().StringVar(&f.resolverFile, "rf", "", "file with one upstream DNS server per line (overrides -r)")
+	cmd.Flags().Float64Var(&f.qps, "qps", 50, "max queries per second, per upstream resolver")
+	cmd.Flags().IntVar(&f.retries, "retries", 2, "retries per query on SERVFAIL/timeout, switching resolvers between attempts")
+	cmd.Flags().IntVar(&f.maxHops, "max-hops", 10, "maximum CNAME hops to follow before giving up")
+	cmd.Flags().BoolVar(&f.wildcardFilter, "wildcard-filter", wildcardDefault, "probe a random subdomain per apex domain and tag/suppress results that just match wildcard DNS")
+	cmd.Flags().StringVar(&f.outputFormat, "of", "txt", "output file format: txt|json|jsonl|csv")
+	cmd.Flags().BoolVar(&f.streamStdout, "stream-stdout", false, "also stream JSONL records to stdout regardless of -of, for e.g. `cnfinder ... | jq`")
+}
+
+// buildResolver returns the Resolver described by f's -r/-rf flags, or
+// the system resolver if neither was set, along with the name recorded
+// on every Result.
+func buildResolver(f *scanFlags) (cnfinder.Resolver, string, error) {
+	switch {
+	case f.resolverFile != "":
+		servers, err := cnfinder.LoadResolversFile(f.resolverFile)
+		if err != nil {
+			return nil, "", err
+		}
+		res, err := cnfinder.NewMultiResolver(servers, f.qps, f.retries)
+		if err != nil {
+			return nil, "", fmt.Errorf("building resolver from %s: %w", f.resolverFile, err)
+		}
+		return res, strings.Join(servers, ","), nil
+	case f.resolverList != "":
+		servers := strings.Split(f.resolverList, ",")
+		res, err := cnfinder.NewMultiResolver(servers, f.qps, f.retries)
+		if err != nil {
+			return nil, "", fmt.Errorf("building resolver from -r: %w", err)
+		}
+		return res, f.resolverList, nil
+	default:
+		return cnfinder.SystemResolver{}, "system", nil
+	}
+}
+
+// openInput resolves f.inputFile to a readable stream: "-" or a piped
+// stdin, or a named file. It mirrors the original tool's behavior of
+// falling back to stdin when no file is given but data is piped in.
+func openInput(f *scanFlags) (io.ReadCloser, error) {
+	if f.inputFile == "-" {
+		logger.Infof("Reading subdomains from stdin (explicit '-')")
+		return io.NopCloser(os.Stdin), nil
+	}
+	if f.inputFile != "" {
+		file, err := os.Open(f.inputFile)
+		if err != nil {
+			return nil, fmt.Errorf("opening input file %s: %w", f.inputFile, err)
+		}
+		return file, nil
+	}
+	stat, err := os.Stdin.Stat()
+	if err != nil {
+		return nil, fmt.Errorf("determining stdin state: %w", err)
+	}
+	if (stat.Mode() & os.ModeCharDevice) != 0 {
+		return nil, fmt.Errorf("no input specified; provide -l <file> or pipe data to stdin")
+	}
+	logger.Infof("Reading subdomains from stdin (piped data)")
+	return io.NopCloser(os.Stdin), nil
+}
+
+// readSubdomains reads one sanitized subdomain per non-empty, non-comment
+// line from r, stripping a leading http:// or https:// scheme or, when
+// there is no scheme, a trailing slash.
+func readSubdomains(r io.Reader) ([]string, error) {
+	subs := make([]string, 0, 1000)
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		clean := stripScheme(line)
+		if clean == "" {
+			continue
+		}
+		subs = append(subs, clean)
+	}
+	return subs, scanner.Err()
+}
+
+func stripScheme(s string) string {
+	s = strings.TrimSpace(s)
+	if strings.HasPrefix(s, "http://") {
+		return s[len("http://"):]
+	}
+	if strings.HasPrefix(s, "https://") {
+		return s[len("https://"):]
+	}
+	return strings.TrimSuffix(s, "/")
+}
+
+// openOutput creates or opens f.outputFile per f.appendMode, ensuring its
+// parent directory exists first.
+func openOutput(f *scanFlags) (*os.File, error) {
+	outDir := filepath.Dir(f.outputFile)
+	if outDir != "" && outDir != "." {
+		if err := os.MkdirAll(outDir, 0o755); err != nil {
+			return nil, fmt.Errorf("creating output directory %s: %w", outDir, err)
+		}
+	}
+	if f.appendMode {
+		return os.OpenFile(f.outputFile, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o644)
+	}
+	return os.Create(f.outputFile)
+}
+
+// runScan wires f's flags into a Scanner, runs it over the parsed input,
+// and writes every Result to the configured output(s). takeoverCheck and
+// fingerprints let each subcommand opt into the fingerprint/HTTP probe
+// without duplicating the surrounding plumbing.
+func runScan(cmd *cobra.Command, f *scanFlags, takeoverCheck bool, fingerprints []cnfinder.Fingerprint, takeoverTimeout time.Duration) error {
+	resolver, resolverName, err := buildResolver(f)
+	if err != nil {
+		return err
+	}
+
+	in, err := openInput(f)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+
+	subs, err := readSubdomains(in)
+	if err != nil {
+		return fmt.Errorf("reading input: %w", err)
+	}
+	if len(subs) == 0 {
+		logger.Infof("No subdomains found in input, exiting.")
+		return nil
+	}
+
+	of, err := openOutput(f)
+	if err != nil {
+		return err
+	}
+	defer of.Close()
+
+	writer, err := cnfinder.NewOutputWriter(f.outputFormat, of)
+	if err != nil {
+		return err
+	}
+
+	var stdoutWriter cnfinder.OutputWriter
+	if f.streamStdout {
+		stdoutWriter = cnfinder.NewJSONLWriter(os.Stdout)
+	}
+
+	scanner := cnfinder.NewScanner(cnfinder.Options{
+		Resolver:        resolver,
+		ResolverName:    resolverName,
+		Workers:         f.workers,
+		Timeout:         f.timeout,
+		MaxHops:         f.maxHops,
+		WildcardFilter:  f.wildcardFilter,
+		Fingerprints:    fingerprints,
+		TakeoverCheck:   takeoverCheck,
+		TakeoverTimeout: takeoverTimeout,
+		Logger:          logger.Default(),
+	})
+
+	scanner.Scan(cmd.Context(), subs, func(res cnfinder.Result) {
+		// Internal statuses (no CNAME, wildcard match, lookup error) only
+		// drive the Logger calls Scanner already made; they're not part
+		// of the documented status enum and must not reach an output file.
+		if cnfinder.IsInternalStatus(res.Status) {
+			return
+		}
+		if err := writer.WriteRecord(res); err != nil {
+			logger.Errorf("failed writing result for %s: %v", res.Input, err)
+		}
+		if stdoutWriter != nil {
+			if err := stdoutWriter.WriteRecord(res); err != nil {
+				logger.Errorf("failed streaming result for %s: %v", res.Input, err)
+			}
+		}
+		if res.Status == cnfinder.StatusOK || res.Status == cnfinder.StatusDangling || res.Status == cnfinder.StatusTakeover {
+			cname := res.Chain[len(res.Chain)-1]
+			logger.Infof("%s > %s", res.Input, cname)
+		}
+	})
+
+	return writer.Close()
+}
+
+var (
+	verboseCount int
+	quiet        bool
+	logJSON      bool
+)
+
+func rootCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:           "cnfinder",
+		Short:         "Resolve CNAME chains across a list of subdomains and flag dangling or takeover-prone ones",
+		SilenceUsage:  true,
+		SilenceErrors: true,
+		PersistentPreRun: func(cmd *cobra.Command, args []string) {
+			logger.SetJSON(logJSON)
+			switch {
+			case quiet:
+				logger.SetLevel(logger.LevelError)
+			case verboseCount > 0:
+				logger.SetLevel(logger.LevelDebug)
+			default:
+				logger.SetLevel(logger.LevelInfo)
+			}
+		},
+	}
+	cmd.PersistentFlags().CountVarP(&verboseCount, "verbose", "v", "enable debug logging (-v, -vv)")
+	cmd.PersistentFlags().BoolVarP(&quiet, "quiet", "q", false, "only report vulnerable findings")
+	cmd.PersistentFlags().BoolVar(&logJSON, "log-json", false, "emit machine-readable JSON log lines to stderr instead of colored text")
+	cmd.AddCommand(newResolveCmd(), newTakeoverCmd(), newChainCmd(), newProbeCmd())
+	return cmd
+}
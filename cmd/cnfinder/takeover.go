@@ -0,0 +1,35 @@
+package main
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/OctaYus/cnfinder/pkg/cnfinder"
+)
+
+// newTakeoverCmd builds `cnfinder takeover`, which resolves CNAME chains
+// same as resolve but additionally matches each CNAME against the
+// fingerprint database and probes candidates over HTTP.
+func newTakeoverCmd() *cobra.Command {
+	var f scanFlags
+	var fingerprintsPath string
+	var takeoverTimeout time.Duration
+
+	cmd := &cobra.Command{
+		Use:   "takeover",
+		Short: "Resolve CNAME chains and flag subdomains vulnerable to takeover",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			fps, err := cnfinder.LoadFingerprints(fingerprintsPath)
+			if err != nil {
+				return fmt.Errorf("loading fingerprints: %w", err)
+			}
+			return runScan(cmd, &f, true, fps, takeoverTimeout)
+		},
+	}
+	addScanFlags(cmd, &f, true)
+	cmd.Flags().StringVar(&fingerprintsPath, "fingerprints", "", "path to a custom fingerprints JSON file (default: embedded database)")
+	cmd.Flags().DurationVar(&takeoverTimeout, "takeover-timeout", 7*time.Second, "HTTP timeout for takeover probes, e.g. 5s")
+	return cmd
+}
@@ -0,0 +1,159 @@
+// Package logger is cnfinder's leveled console logger. It replaces the
+// old log.Printf/printColored/log.Fatalf mix with a single place that
+// decides what gets printed and how: colored tags on a TTY, plain text
+// when redirected or NO_COLOR is set, and JSON lines when -log-json asks
+// for machine-readable output.
+package logger
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"sync"
+
+	"golang.org/x/term"
+)
+
+// Level is a logger verbosity threshold; messages below the configured
+// level are dropped.
+type Level int
+
+const (
+	LevelDebug Level = iota
+	LevelInfo
+	LevelWarn
+	LevelError
+)
+
+const (
+	colorRed   = "\033[31m"
+	colorCyan  = "\033[36m"
+	colorReset = "\033[0m"
+)
+
+// Logger writes leveled, optionally colored or JSON-encoded messages to
+// an output stream. The zero value is not usable; construct one with
+// New. Its four *f methods satisfy cnfinder.Logger, so a Logger can be
+// handed straight to a Scanner as well as used for the CLI's own
+// diagnostics.
+type Logger struct {
+	mu    sync.Mutex
+	out   io.Writer
+	level Level
+	json  bool
+	color bool
+}
+
+// New builds a Logger writing to out, defaulting to LevelInfo and to
+// colored output when out is a terminal and NO_COLOR is unset.
+func New(out io.Writer) *Logger {
+	return &Logger{out: out, level: LevelInfo, color: colorSupported(out)}
+}
+
+func colorSupported(out io.Writer) bool {
+	if os.Getenv("NO_COLOR") != "" {
+		return false
+	}
+	f, ok := out.(*os.File)
+	if !ok {
+		return false
+	}
+	return term.IsTerminal(int(f.Fd()))
+}
+
+// SetLevel changes the minimum level l emits.
+func (l *Logger) SetLevel(level Level) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.level = level
+}
+
+// SetJSON switches l between colored/plain text and newline-delimited
+// JSON. JSON output is never colored.
+func (l *Logger) SetJSON(enabled bool) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.json = enabled
+	if enabled {
+		l.color = false
+	}
+}
+
+func (l *Logger) Debugf(format string, args ...interface{}) { l.logf(LevelDebug, format, args...) }
+func (l *Logger) Infof(format string, args ...interface{})  { l.logf(LevelInfo, format, args...) }
+func (l *Logger) Warnf(format string, args ...interface{})  { l.logf(LevelWarn, format, args...) }
+func (l *Logger) Errorf(format string, args ...interface{}) { l.logf(LevelError, format, args...) }
+
+func (l *Logger) logf(level Level, format string, args ...interface{}) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if level < l.level {
+		return
+	}
+	msg := fmt.Sprintf(format, args...)
+
+	if l.json {
+		rec := struct {
+			Level string `json:"level"`
+			Msg   string `json:"msg"`
+		}{levelName(level), msg}
+		data, err := json.Marshal(rec)
+		if err != nil {
+			return
+		}
+		fmt.Fprintln(l.out, string(data))
+		return
+	}
+
+	tag, color := tagAndColor(level)
+	if l.color {
+		fmt.Fprintf(l.out, "%s%s %s%s\n", color, tag, msg, colorReset)
+	} else {
+		fmt.Fprintf(l.out, "%s %s\n", tag, msg)
+	}
+}
+
+func levelName(level Level) string {
+	switch level {
+	case LevelDebug:
+		return "debug"
+	case LevelInfo:
+		return "info"
+	case LevelWarn:
+		return "warn"
+	case LevelError:
+		return "error"
+	default:
+		return "unknown"
+	}
+}
+
+func tagAndColor(level Level) (tag, color string) {
+	switch level {
+	case LevelDebug:
+		return "[-]", colorCyan
+	case LevelInfo:
+		return "[?]", colorCyan
+	case LevelWarn:
+		return "[!]", colorRed
+	default:
+		return "[-]", colorRed
+	}
+}
+
+// std is the default Logger used by the package-level functions below,
+// wired to stderr so the CLI's stdout stays clean for -stream-stdout.
+var std = New(os.Stderr)
+
+// Default returns the package's default Logger, e.g. for handing to a
+// cnfinder.Scanner as its Options.Logger.
+func Default() *Logger { return std }
+
+func SetLevel(level Level)                      { std.SetLevel(level) }
+func SetJSON(enabled bool)                      { std.SetJSON(enabled) }
+func ColorEnabled() bool                        { std.mu.Lock(); defer std.mu.Unlock(); return std.color }
+func Debugf(format string, args ...interface{}) { std.Debugf(format, args...) }
+func Infof(format string, args ...interface{})  { std.Infof(format, args...) }
+func Warnf(format string, args ...interface{})  { std.Warnf(format, args...) }
+func Errorf(format string, args ...interface{}) { std.Errorf(format, args...) }
@@ -0,0 +1,70 @@
+package logger
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func newTestLogger() (*Logger, *bytes.Buffer) {
+	var buf bytes.Buffer
+	l := New(&buf)
+	l.color = false // buf is never a terminal, but be explicit for clarity
+	return l, &buf
+}
+
+func TestLoggerLevelFiltering(t *testing.T) {
+	l, buf := newTestLogger()
+	l.SetLevel(LevelWarn)
+
+	l.Debugf("debug message")
+	l.Infof("info message")
+	if buf.Len() != 0 {
+		t.Fatalf("expected nothing below LevelWarn to be written, got %q", buf.String())
+	}
+
+	l.Warnf("warn message")
+	if !strings.Contains(buf.String(), "warn message") {
+		t.Errorf("expected warn message to be written, got %q", buf.String())
+	}
+}
+
+func TestLoggerPlainText(t *testing.T) {
+	l, buf := newTestLogger()
+	l.Infof("hello %s", "world")
+
+	got := buf.String()
+	if !strings.Contains(got, "hello world") {
+		t.Errorf("got %q, want it to contain %q", got, "hello world")
+	}
+	if strings.Contains(got, "\033[") {
+		t.Errorf("got %q, want no color escapes", got)
+	}
+}
+
+func TestLoggerJSON(t *testing.T) {
+	l, buf := newTestLogger()
+	l.SetJSON(true)
+	l.Errorf("boom %d", 42)
+
+	var rec struct {
+		Level string `json:"level"`
+		Msg   string `json:"msg"`
+	}
+	if err := json.Unmarshal(buf.Bytes(), &rec); err != nil {
+		t.Fatalf("output is not valid JSON: %v\n%s", err, buf.String())
+	}
+	if rec.Level != "error" || rec.Msg != "boom 42" {
+		t.Errorf("got %+v, want level=error msg=\"boom 42\"", rec)
+	}
+}
+
+func TestLoggerSetJSONDisablesColor(t *testing.T) {
+	l, _ := newTestLogger()
+	l.color = true
+	l.SetJSON(true)
+	if l.color {
+		t.Error("SetJSON(true) left color enabled, want it forced off")
+	}
+}
@@ -0,0 +1,127 @@
+package cnfinder
+
+import (
+	"context"
+	"crypto/rand"
+	"fmt"
+	"strings"
+)
+
+// Chain is the outcome of walking a subdomain's full CNAME chain to its
+// terminal A/AAAA records.
+type Chain struct {
+	Hops        []string // CNAME targets in order, not including the original name
+	TerminalIPs []string
+	Dangling    bool // an intermediate hop has no A record / NXDOMAIN at its authoritative nameserver
+	Cycle       bool
+}
+
+// ResolveChain follows CNAME hops for name until it terminates, a cycle
+// is detected, or maxHops is reached, then resolves the terminal name's
+// A/AAAA records.
+func ResolveChain(ctx context.Context, res Resolver, name string, maxHops int) (Chain, error) {
+	seen := map[string]struct{}{normalize(name): {}}
+	current := normalize(name)
+	var hops []string
+
+	for i := 0; i < maxHops; i++ {
+		next, err := res.LookupCNAME(ctx, current)
+		if err != nil {
+			if isNXDOMAIN(err) {
+				if len(hops) == 0 {
+					// current is the original input name: it doesn't
+					// exist at all, so this isn't a dangling CNAME,
+					// it's an NXDOMAIN. Propagate it as an error so
+					// callers can tell the two apart.
+					return Chain{}, err
+				}
+				return Chain{Hops: hops, Dangling: true}, nil
+			}
+			return Chain{Hops: hops}, err
+		}
+		if next == current {
+			// No further CNAME: current is the terminal name.
+			break
+		}
+		if _, ok := seen[next]; ok {
+			return Chain{Hops: hops, Cycle: true}, nil
+		}
+		seen[next] = struct{}{}
+		hops = append(hops, next)
+		current = next
+	}
+
+	ips, err := res.LookupHost(ctx, current)
+	if err != nil {
+		if isNXDOMAIN(err) && len(hops) > 0 {
+			return Chain{Hops: hops, Dangling: true}, nil
+		}
+		return Chain{Hops: hops}, err
+	}
+	if len(ips) == 0 && len(hops) > 0 {
+		return Chain{Hops: hops, Dangling: true}, nil
+	}
+	return Chain{Hops: hops, TerminalIPs: ips}, nil
+}
+
+// RegistrableDomain returns a best-effort apex domain for name by
+// keeping its last two labels. This doesn't consult a public-suffix
+// list, so multi-part TLDs (co.uk, github.io) are handled imprecisely;
+// good enough for grouping wildcard baselines per sweep.
+func RegistrableDomain(name string) string {
+	labels := strings.Split(normalize(name), ".")
+	if len(labels) < 2 {
+		return normalize(name)
+	}
+	return strings.Join(labels[len(labels)-2:], ".")
+}
+
+// randomLabel returns a random 32-character lowercase-hex label used to
+// probe for wildcard DNS.
+func randomLabel() (string, error) {
+	buf := make([]byte, 16)
+	if _, err := rand.Read(buf); err != nil {
+		return "", fmt.Errorf("generating random label: %w", err)
+	}
+	return fmt.Sprintf("%x", buf), nil
+}
+
+// WildcardBaseline resolves the chain for a random, certainly-unregistered
+// subdomain of apex, to use as a point of comparison when deciding
+// whether a real result is actually just wildcard DNS.
+func WildcardBaseline(ctx context.Context, res Resolver, apex string, maxHops int) (Chain, error) {
+	label, err := randomLabel()
+	if err != nil {
+		return Chain{}, err
+	}
+	return ResolveChain(ctx, res, label+"."+apex, maxHops)
+}
+
+// MatchesWildcard reports whether c resolved to the same chain terminus
+// as baseline, meaning it's likely just wildcard DNS rather than a real
+// record.
+func MatchesWildcard(c, baseline Chain) bool {
+	if len(baseline.Hops) == 0 && len(baseline.TerminalIPs) == 0 {
+		return false
+	}
+	if len(c.Hops) > 0 && len(baseline.Hops) > 0 {
+		return c.Hops[len(c.Hops)-1] == baseline.Hops[len(baseline.Hops)-1]
+	}
+	return sameIPSet(c.TerminalIPs, baseline.TerminalIPs)
+}
+
+func sameIPSet(a, b []string) bool {
+	if len(a) == 0 || len(a) != len(b) {
+		return false
+	}
+	set := make(map[string]struct{}, len(a))
+	for _, ip := range a {
+		set[ip] = struct{}{}
+	}
+	for _, ip := range b {
+		if _, ok := set[ip]; !ok {
+			return false
+		}
+	}
+	return true
+}
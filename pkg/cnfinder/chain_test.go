@@ -0,0 +1,140 @@
+package cnfinder
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+)
+
+// fakeResolver serves canned CNAME/host answers for chain tests, keyed by
+// normalized name.
+type fakeResolver struct {
+	cnames map[string]string // name -> CNAME target; absent means "no CNAME"
+	nx     map[string]bool   // name -> NXDOMAIN
+	hosts  map[string][]string
+}
+
+func (f *fakeResolver) LookupCNAME(ctx context.Context, name string) (string, error) {
+	if f.nx[name] {
+		return "", fmt.Errorf("%s: %w", name, errNXDOMAIN)
+	}
+	if target, ok := f.cnames[name]; ok {
+		return target, nil
+	}
+	return name, nil
+}
+
+func (f *fakeResolver) LookupHost(ctx context.Context, name string) ([]string, error) {
+	if f.nx[name] {
+		return nil, fmt.Errorf("%s: %w", name, errNXDOMAIN)
+	}
+	return f.hosts[name], nil
+}
+
+func TestResolveChainNXDOMAINOnFirstHop(t *testing.T) {
+	res := &fakeResolver{nx: map[string]bool{"nonexistent.example.com": true}}
+
+	chain, err := ResolveChain(context.Background(), res, "nonexistent.example.com", 10)
+	if err == nil {
+		t.Fatal("ResolveChain = nil error, want NXDOMAIN propagated")
+	}
+	if !isNXDOMAIN(err) {
+		t.Errorf("ResolveChain err = %v, want an NXDOMAIN error", err)
+	}
+	if len(chain.Hops) != 0 {
+		t.Errorf("chain.Hops = %v, want empty", chain.Hops)
+	}
+}
+
+func TestResolveChainDanglingMidChain(t *testing.T) {
+	res := &fakeResolver{
+		cnames: map[string]string{"www.example.com": "dangling.example.net"},
+		nx:     map[string]bool{"dangling.example.net": true},
+	}
+
+	chain, err := ResolveChain(context.Background(), res, "www.example.com", 10)
+	if err != nil {
+		t.Fatalf("ResolveChain: %v", err)
+	}
+	if !chain.Dangling {
+		t.Error("chain.Dangling = false, want true")
+	}
+	if len(chain.Hops) != 1 || chain.Hops[0] != "dangling.example.net" {
+		t.Errorf("chain.Hops = %v, want [dangling.example.net]", chain.Hops)
+	}
+}
+
+func TestResolveChainTerminates(t *testing.T) {
+	res := &fakeResolver{
+		cnames: map[string]string{"www.example.com": "edge.example.net"},
+		hosts:  map[string][]string{"edge.example.net": {"203.0.113.1"}},
+	}
+
+	chain, err := ResolveChain(context.Background(), res, "www.example.com", 10)
+	if err != nil {
+		t.Fatalf("ResolveChain: %v", err)
+	}
+	if chain.Dangling {
+		t.Error("chain.Dangling = true, want false")
+	}
+	if len(chain.TerminalIPs) != 1 || chain.TerminalIPs[0] != "203.0.113.1" {
+		t.Errorf("chain.TerminalIPs = %v, want [203.0.113.1]", chain.TerminalIPs)
+	}
+}
+
+func TestResolveChainCycle(t *testing.T) {
+	res := &fakeResolver{
+		cnames: map[string]string{
+			"a.example.com": "b.example.com",
+			"b.example.com": "a.example.com",
+		},
+	}
+
+	chain, err := ResolveChain(context.Background(), res, "a.example.com", 10)
+	if err != nil {
+		t.Fatalf("ResolveChain: %v", err)
+	}
+	if !chain.Cycle {
+		t.Error("chain.Cycle = false, want true")
+	}
+}
+
+func TestRegistrableDomain(t *testing.T) {
+	cases := map[string]string{
+		"www.example.com": "example.com",
+		"example.com":     "example.com",
+		"localhost":       "localhost",
+	}
+	for in, want := range cases {
+		if got := RegistrableDomain(in); got != want {
+			t.Errorf("RegistrableDomain(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
+
+func TestMatchesWildcard(t *testing.T) {
+	baseline := Chain{TerminalIPs: []string{"203.0.113.9"}}
+	match := Chain{TerminalIPs: []string{"203.0.113.9"}}
+	mismatch := Chain{TerminalIPs: []string{"203.0.113.10"}}
+
+	if !MatchesWildcard(match, baseline) {
+		t.Error("MatchesWildcard(match, baseline) = false, want true")
+	}
+	if MatchesWildcard(mismatch, baseline) {
+		t.Error("MatchesWildcard(mismatch, baseline) = true, want false")
+	}
+	if MatchesWildcard(match, Chain{}) {
+		t.Error("MatchesWildcard(match, empty baseline) = true, want false")
+	}
+}
+
+func TestIsNXDOMAINWrapped(t *testing.T) {
+	wrapped := fmt.Errorf("lookup failed: %w", errNXDOMAIN)
+	if !isNXDOMAIN(wrapped) {
+		t.Error("isNXDOMAIN(wrapped errNXDOMAIN) = false, want true")
+	}
+	if isNXDOMAIN(errors.New("some other error")) {
+		t.Error("isNXDOMAIN(unrelated error) = true, want false")
+	}
+}
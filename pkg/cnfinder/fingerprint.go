@@ -0,0 +1,60 @@
+package cnfinder
+
+import (
+	"embed"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+)
+
+//go:embed fingerprints.json
+var embeddedFingerprints embed.FS
+
+// Fingerprint describes one vulnerable-service signature used for
+// subdomain takeover detection. A CNAME is considered a candidate for a
+// given fingerprint when it ends in one of CNAMESuffixes; the HTTP probe
+// then confirms the match against BodySignatures/HTTPStatuses.
+type Fingerprint struct {
+	Service        string   `json:"service"`
+	CNAMESuffixes  []string `json:"cname_suffixes"`
+	BodySignatures []string `json:"body_signatures"`
+	HTTPStatuses   []int    `json:"http_statuses"`
+}
+
+// LoadFingerprints loads the fingerprint database from path if given,
+// otherwise falls back to the database embedded in the binary.
+func LoadFingerprints(path string) ([]Fingerprint, error) {
+	var data []byte
+	var err error
+	if path != "" {
+		data, err = os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("reading fingerprints file %s: %w", path, err)
+		}
+	} else {
+		data, err = embeddedFingerprints.ReadFile("fingerprints.json")
+		if err != nil {
+			return nil, fmt.Errorf("reading embedded fingerprints: %w", err)
+		}
+	}
+	var fps []Fingerprint
+	if err := json.Unmarshal(data, &fps); err != nil {
+		return nil, fmt.Errorf("parsing fingerprints: %w", err)
+	}
+	return fps, nil
+}
+
+// MatchFingerprint returns the first fingerprint whose CNAME suffix
+// matches cname, or nil if none apply.
+func MatchFingerprint(cname string, fps []Fingerprint) *Fingerprint {
+	lc := strings.ToLower(cname)
+	for i := range fps {
+		for _, suffix := range fps[i].CNAMESuffixes {
+			if strings.HasSuffix(lc, strings.ToLower(suffix)) {
+				return &fps[i]
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,41 @@
+package cnfinder
+
+import "testing"
+
+func TestMatchFingerprint(t *testing.T) {
+	fps := []Fingerprint{
+		{Service: "github", CNAMESuffixes: []string{"github.io", "github.map.fastly.net"}},
+		{Service: "herokuapp", CNAMESuffixes: []string{"herokudns.com"}},
+	}
+
+	if fp := MatchFingerprint("myorg.github.io", fps); fp == nil || fp.Service != "github" {
+		t.Fatalf("MatchFingerprint(myorg.github.io) = %v, want github", fp)
+	}
+	if fp := MatchFingerprint("MYORG.GITHUB.IO", fps); fp == nil || fp.Service != "github" {
+		t.Fatalf("MatchFingerprint is case-sensitive, want a case-insensitive match")
+	}
+	if fp := MatchFingerprint("example.com", fps); fp != nil {
+		t.Fatalf("MatchFingerprint(example.com) = %v, want nil", fp)
+	}
+}
+
+func TestClassify(t *testing.T) {
+	fp := &Fingerprint{
+		Service:        "github",
+		BodySignatures: []string{"There isn't a GitHub Pages site here"},
+		HTTPStatuses:   []int{404},
+	}
+
+	if got := classify(fp, 404, "There isn't a GitHub Pages site here."); got != StatusVulnerable {
+		t.Errorf("classify(match, match) = %q, want %q", got, StatusVulnerable)
+	}
+	if got := classify(fp, 200, "There isn't a GitHub Pages site here."); got != StatusNeedsManualReview {
+		t.Errorf("classify(signature, no status match) = %q, want %q", got, StatusNeedsManualReview)
+	}
+	if got := classify(fp, 404, "all good here"); got != StatusNeedsManualReview {
+		t.Errorf("classify(status match, no signature) = %q, want %q", got, StatusNeedsManualReview)
+	}
+	if got := classify(fp, 200, "all good here"); got != StatusNotVulnerable {
+		t.Errorf("classify(no match) = %q, want %q", got, StatusNotVulnerable)
+	}
+}
@@ -0,0 +1,172 @@
+package cnfinder
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// Status values carried on every Result, describing what happened to a
+// single input line.
+const (
+	StatusOK       = "ok"
+	StatusNXDomain = "nxdomain"
+	StatusTimeout  = "timeout"
+	StatusDangling = "dangling"
+	StatusTakeover = "takeover"
+)
+
+// Result is one scan result, shaped for consumption by downstream
+// tooling (jq, Splunk, ELK) as much as for the human-readable writers.
+type Result struct {
+	Input       string   `json:"input"`
+	Chain       []string `json:"chain"`
+	TerminalIP  []string `json:"terminal_ip"`
+	Status      string   `json:"status"`
+	Fingerprint string   `json:"fingerprint,omitempty"`
+	HTTPStatus  int      `json:"http_status,omitempty"`
+	ElapsedMS   int64    `json:"elapsed_ms"`
+	Resolver    string   `json:"resolver,omitempty"`
+}
+
+// OutputWriter is implemented once per supported -of format.
+type OutputWriter interface {
+	WriteRecord(r Result) error
+	Close() error
+}
+
+// NewOutputWriter builds the OutputWriter for format ("json", "jsonl",
+// "csv" or "txt"), writing to w.
+func NewOutputWriter(format string, w io.Writer) (OutputWriter, error) {
+	switch format {
+	case "json":
+		return &jsonWriter{w: w}, nil
+	case "jsonl":
+		return &JSONLWriter{w: w}, nil
+	case "csv":
+		return newCSVWriter(w)
+	case "txt", "":
+		return &txtWriter{w: w}, nil
+	default:
+		return nil, fmt.Errorf("unknown output format %q (want json, jsonl, csv or txt)", format)
+	}
+}
+
+// jsonWriter emits a single JSON array, one record per element.
+type jsonWriter struct {
+	w       io.Writer
+	wrote   bool
+	started bool
+}
+
+func (j *jsonWriter) WriteRecord(r Result) error {
+	if !j.started {
+		if _, err := io.WriteString(j.w, "["); err != nil {
+			return err
+		}
+		j.started = true
+	}
+	if j.wrote {
+		if _, err := io.WriteString(j.w, ","); err != nil {
+			return err
+		}
+	}
+	data, err := json.Marshal(r)
+	if err != nil {
+		return err
+	}
+	if _, err := j.w.Write(data); err != nil {
+		return err
+	}
+	j.wrote = true
+	return nil
+}
+
+func (j *jsonWriter) Close() error {
+	if !j.started {
+		_, err := io.WriteString(j.w, "[]")
+		return err
+	}
+	_, err := io.WriteString(j.w, "]")
+	return err
+}
+
+// JSONLWriter emits one JSON object per line (NDJSON). It's exported
+// because cmd/cnfinder also uses it directly for -stream-stdout, which
+// always streams JSONL regardless of -of.
+type JSONLWriter struct {
+	w io.Writer
+}
+
+// NewJSONLWriter builds a JSONLWriter over w.
+func NewJSONLWriter(w io.Writer) *JSONLWriter {
+	return &JSONLWriter{w: w}
+}
+
+func (j *JSONLWriter) WriteRecord(r Result) error {
+	data, err := json.Marshal(r)
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintf(j.w, "%s\n", data)
+	return err
+}
+
+func (j *JSONLWriter) Close() error { return nil }
+
+// csvWriter emits one row per record with a fixed header.
+type csvWriter struct {
+	cw *csv.Writer
+}
+
+func newCSVWriter(w io.Writer) (*csvWriter, error) {
+	cw := csv.NewWriter(w)
+	if err := cw.Write([]string{"input", "chain", "terminal_ip", "status", "fingerprint", "http_status", "elapsed_ms", "resolver"}); err != nil {
+		return nil, err
+	}
+	return &csvWriter{cw: cw}, nil
+}
+
+func (c *csvWriter) WriteRecord(r Result) error {
+	return c.cw.Write([]string{
+		r.Input,
+		strings.Join(r.Chain, " > "),
+		strings.Join(r.TerminalIP, ","),
+		r.Status,
+		r.Fingerprint,
+		strconv.Itoa(r.HTTPStatus),
+		strconv.FormatInt(r.ElapsedMS, 10),
+		r.Resolver,
+	})
+}
+
+func (c *csvWriter) Close() error {
+	c.cw.Flush()
+	return c.cw.Error()
+}
+
+// txtWriter preserves the original "sub > hop > hop" human-readable
+// format, with any non-ok status appended as a bracketed tag.
+type txtWriter struct {
+	w io.Writer
+}
+
+func (t *txtWriter) WriteRecord(r Result) error {
+	line := r.Input
+	if len(r.Chain) > 0 {
+		line += " > " + strings.Join(r.Chain, " > ")
+	}
+	if r.Status != StatusOK {
+		line += fmt.Sprintf(" [%s]", r.Status)
+	}
+	if r.Fingerprint != "" {
+		line += fmt.Sprintf(" [%s]", r.Fingerprint)
+	}
+	_, err := fmt.Fprintf(t.w, "%s\n", line)
+	return err
+}
+
+func (t *txtWriter) Close() error { return nil }
@@ -0,0 +1,128 @@
+package cnfinder
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func TestNewOutputWriterUnknownFormat(t *testing.T) {
+	if _, err := NewOutputWriter("yaml", &bytes.Buffer{}); err == nil {
+		t.Fatal("NewOutputWriter(\"yaml\", ...) = nil error, want an error")
+	}
+}
+
+func TestJSONWriter(t *testing.T) {
+	var buf bytes.Buffer
+	w, err := NewOutputWriter("json", &buf)
+	if err != nil {
+		t.Fatalf("NewOutputWriter: %v", err)
+	}
+	if err := w.WriteRecord(Result{Input: "a.example.com", Status: StatusOK}); err != nil {
+		t.Fatalf("WriteRecord: %v", err)
+	}
+	if err := w.WriteRecord(Result{Input: "b.example.com", Status: StatusDangling}); err != nil {
+		t.Fatalf("WriteRecord: %v", err)
+	}
+	if err := w.Close(); err != nil {
+		t.Fatalf("Close: %v", err)
+	}
+
+	var got []Result
+	if err := json.Unmarshal(buf.Bytes(), &got); err != nil {
+		t.Fatalf("output is not a valid JSON array: %v\n%s", err, buf.String())
+	}
+	if len(got) != 2 || got[0].Input != "a.example.com" || got[1].Status != StatusDangling {
+		t.Errorf("got %+v, want 2 records for a.example.com (ok) and b.example.com (dangling)", got)
+	}
+}
+
+func TestJSONWriterEmpty(t *testing.T) {
+	var buf bytes.Buffer
+	w, _ := NewOutputWriter("json", &buf)
+	if err := w.Close(); err != nil {
+		t.Fatalf("Close: %v", err)
+	}
+	if buf.String() != "[]" {
+		t.Errorf("empty json output = %q, want []", buf.String())
+	}
+}
+
+func TestJSONLWriter(t *testing.T) {
+	var buf bytes.Buffer
+	w := NewJSONLWriter(&buf)
+	if err := w.WriteRecord(Result{Input: "a.example.com", Status: StatusOK}); err != nil {
+		t.Fatalf("WriteRecord: %v", err)
+	}
+	if err := w.WriteRecord(Result{Input: "b.example.com", Status: StatusTakeover}); err != nil {
+		t.Fatalf("WriteRecord: %v", err)
+	}
+
+	lines := strings.Split(strings.TrimRight(buf.String(), "\n"), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("got %d lines, want 2:\n%s", len(lines), buf.String())
+	}
+	var r Result
+	if err := json.Unmarshal([]byte(lines[1]), &r); err != nil {
+		t.Fatalf("line 2 is not valid JSON: %v", err)
+	}
+	if r.Status != StatusTakeover {
+		t.Errorf("line 2 status = %q, want %q", r.Status, StatusTakeover)
+	}
+}
+
+func TestCSVWriter(t *testing.T) {
+	var buf bytes.Buffer
+	w, err := NewOutputWriter("csv", &buf)
+	if err != nil {
+		t.Fatalf("NewOutputWriter: %v", err)
+	}
+	r := Result{
+		Input:      "a.example.com",
+		Chain:      []string{"b.example.net", "c.example.net"},
+		TerminalIP: []string{"203.0.113.1", "203.0.113.2"},
+		Status:     StatusDangling,
+	}
+	if err := w.WriteRecord(r); err != nil {
+		t.Fatalf("WriteRecord: %v", err)
+	}
+	if err := w.Close(); err != nil {
+		t.Fatalf("Close: %v", err)
+	}
+
+	out := buf.String()
+	if !strings.Contains(out, "input,chain,terminal_ip,status") {
+		t.Errorf("missing CSV header, got:\n%s", out)
+	}
+	if !strings.Contains(out, "b.example.net > c.example.net") {
+		t.Errorf("chain not joined with \" > \", got:\n%s", out)
+	}
+	if !strings.Contains(out, "203.0.113.1,203.0.113.2") {
+		t.Errorf("terminal IPs not joined with \",\", got:\n%s", out)
+	}
+}
+
+func TestTxtWriter(t *testing.T) {
+	var buf bytes.Buffer
+	w, err := NewOutputWriter("txt", &buf)
+	if err != nil {
+		t.Fatalf("NewOutputWriter: %v", err)
+	}
+	ok := Result{Input: "a.example.com", Chain: []string{"edge.example.net"}, Status: StatusOK}
+	if err := w.WriteRecord(ok); err != nil {
+		t.Fatalf("WriteRecord: %v", err)
+	}
+	tagged := Result{Input: "b.example.com", Chain: []string{"dangling.example.net"}, Status: StatusDangling}
+	if err := w.WriteRecord(tagged); err != nil {
+		t.Fatalf("WriteRecord: %v", err)
+	}
+
+	lines := strings.Split(strings.TrimRight(buf.String(), "\n"), "\n")
+	if lines[0] != "a.example.com > edge.example.net" {
+		t.Errorf("ok line = %q, want no status tag", lines[0])
+	}
+	if lines[1] != "b.example.com > dangling.example.net [dangling]" {
+		t.Errorf("dangling line = %q, want a [dangling] tag", lines[1])
+	}
+}
@@ -0,0 +1,73 @@
+package cnfinder
+
+import (
+	"fmt"
+	"runtime"
+	"sync"
+	"time"
+)
+
+// Pair is one already-resolved "sub > cname" line, as produced by a
+// prior resolve/chain run and read back in by the probe subcommand.
+type Pair struct {
+	Sub   string
+	CNAME string
+}
+
+// Probe runs only the HTTP takeover check for each pair, skipping DNS
+// entirely. It's the library behind `cnfinder probe`, which verifies
+// fingerprint matches out of a previously generated mapping file without
+// re-resolving anything.
+func Probe(pairs []Pair, fps []Fingerprint, timeout time.Duration, workers int) []Result {
+	if workers <= 0 {
+		workers = runtime.NumCPU()
+	}
+
+	jobs := make(chan Pair)
+	results := make(chan Result)
+	var wg sync.WaitGroup
+
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for p := range jobs {
+				results <- probeOne(p, fps, timeout)
+			}
+		}()
+	}
+
+	go func() {
+		for _, p := range pairs {
+			jobs <- p
+		}
+		close(jobs)
+	}()
+
+	go func() {
+		wg.Wait()
+		close(results)
+	}()
+
+	out := make([]Result, 0, len(pairs))
+	for res := range results {
+		out = append(out, res)
+	}
+	return out
+}
+
+func probeOne(p Pair, fps []Fingerprint, timeout time.Duration) Result {
+	res := Result{Input: p.Sub, Chain: []string{p.CNAME}, Status: StatusOK}
+
+	fp := MatchFingerprint(p.CNAME, fps)
+	if fp == nil {
+		return res
+	}
+	finding := CheckTakeover(p.Sub, fp, timeout)
+	res.Fingerprint = fmt.Sprintf("%s:%s", finding.Status, finding.Service)
+	res.HTTPStatus = finding.HTTPStatus
+	if finding.Status == StatusVulnerable {
+		res.Status = StatusTakeover
+	}
+	return res
+}
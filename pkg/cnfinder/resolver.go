@@ -0,0 +1,233 @@
+package cnfinder
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"math/rand"
+	"net"
+	"os"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/miekg/dns"
+)
+
+// Resolver abstracts CNAME lookups so the worker pool can run against the
+// host's system resolver or against a user-supplied list of upstream DNS
+// servers.
+type Resolver interface {
+	LookupCNAME(ctx context.Context, name string) (string, error)
+	LookupHost(ctx context.Context, name string) ([]string, error)
+}
+
+// errNXDOMAIN signals that name does not exist, as distinct from "it
+// exists but has no record of the requested type".
+var errNXDOMAIN = fmt.Errorf("NXDOMAIN")
+
+// isNXDOMAIN reports whether err indicates the queried name does not
+// exist, across both the system resolver and the miekg/dns backend.
+func isNXDOMAIN(err error) bool {
+	if err == nil {
+		return false
+	}
+	if dnsErr, ok := err.(*net.DNSError); ok {
+		return dnsErr.IsNotFound
+	}
+	return errors.Is(err, errNXDOMAIN)
+}
+
+// SystemResolver delegates to net.DefaultResolver, i.e. whatever the OS
+// is configured to use. This is the default and requires no flags.
+type SystemResolver struct{}
+
+func (SystemResolver) LookupCNAME(ctx context.Context, name string) (string, error) {
+	cname, err := net.DefaultResolver.LookupCNAME(ctx, name)
+	if err != nil {
+		return "", err
+	}
+	return normalize(cname), nil
+}
+
+func (SystemResolver) LookupHost(ctx context.Context, name string) ([]string, error) {
+	return net.DefaultResolver.LookupHost(ctx, name)
+}
+
+// tokenBucket is a minimal token-bucket rate limiter: one resolver gets
+// one bucket, refilled at qps and drained by each query.
+type tokenBucket struct {
+	mu       sync.Mutex
+	tokens   float64
+	max      float64
+	rate     float64
+	lastFill time.Time
+}
+
+func newTokenBucket(qps float64) *tokenBucket {
+	return &tokenBucket{tokens: qps, max: qps, rate: qps, lastFill: time.Now()}
+}
+
+func (b *tokenBucket) wait(ctx context.Context) error {
+	if b.rate <= 0 {
+		return nil
+	}
+	for {
+		b.mu.Lock()
+		now := time.Now()
+		b.tokens += now.Sub(b.lastFill).Seconds() * b.rate
+		if b.tokens > b.max {
+			b.tokens = b.max
+		}
+		b.lastFill = now
+		if b.tokens >= 1 {
+			b.tokens--
+			b.mu.Unlock()
+			return nil
+		}
+		b.mu.Unlock()
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(10 * time.Millisecond):
+		}
+	}
+}
+
+// MultiResolver round-robins CNAME queries across a set of upstream DNS
+// servers via github.com/miekg/dns, rate limiting each server
+// independently and retrying on SERVFAIL/timeout by moving on to the
+// next server.
+type MultiResolver struct {
+	client  *dns.Client
+	servers []string
+	buckets []*tokenBucket
+	next    uint64
+	retries int
+}
+
+// NewMultiResolver builds a resolver over servers (each "host:port", ":53"
+// appended if missing), rate limited to qps queries/sec per server and
+// retrying up to retries times. It returns an error if servers is empty,
+// since a resolver with no upstreams can't round-robin anything.
+func NewMultiResolver(servers []string, qps float64, retries int) (*MultiResolver, error) {
+	if len(servers) == 0 {
+		return nil, fmt.Errorf("no upstream DNS servers given")
+	}
+	buckets := make([]*tokenBucket, len(servers))
+	for i := range servers {
+		if _, _, err := net.SplitHostPort(servers[i]); err != nil {
+			servers[i] = net.JoinHostPort(strings.Trim(servers[i], "[]"), "53")
+		}
+		buckets[i] = newTokenBucket(qps)
+	}
+	return &MultiResolver{
+		client:  &dns.Client{},
+		servers: servers,
+		buckets: buckets,
+		retries: retries,
+	}, nil
+}
+
+func (m *MultiResolver) LookupCNAME(ctx context.Context, name string) (string, error) {
+	resp, err := m.exchange(ctx, name, dns.TypeCNAME)
+	if err != nil {
+		return "", err
+	}
+	for _, rr := range resp.Answer {
+		if c, ok := rr.(*dns.CNAME); ok {
+			return normalize(c.Target), nil
+		}
+	}
+	// No CNAME answer but a valid response: nothing to retry for.
+	return name, nil
+}
+
+// LookupHost queries A and AAAA records for name, round-robining and
+// retrying the same as LookupCNAME.
+func (m *MultiResolver) LookupHost(ctx context.Context, name string) ([]string, error) {
+	var ips []string
+	for _, qtype := range []uint16{dns.TypeA, dns.TypeAAAA} {
+		resp, err := m.exchange(ctx, name, qtype)
+		if err != nil {
+			return nil, err
+		}
+		for _, rr := range resp.Answer {
+			switch r := rr.(type) {
+			case *dns.A:
+				ips = append(ips, r.A.String())
+			case *dns.AAAA:
+				ips = append(ips, r.AAAA.String())
+			}
+		}
+	}
+	return ips, nil
+}
+
+// exchange sends a single qtype query for name, round-robining across
+// m.servers and retrying up to m.retries times - switching to the next
+// server each attempt - on transport errors or SERVFAIL. It returns the
+// raw response so callers can pull out whichever RR type they asked for,
+// and surfaces RcodeNameError as errNXDOMAIN.
+func (m *MultiResolver) exchange(ctx context.Context, name string, qtype uint16) (*dns.Msg, error) {
+	var lastErr error
+	n := len(m.servers)
+	start := int(atomic.AddUint64(&m.next, 1))
+
+	for attempt := 0; attempt <= m.retries; attempt++ {
+		idx := (start + attempt) % n
+		if err := m.buckets[idx].wait(ctx); err != nil {
+			return nil, err
+		}
+
+		msg := new(dns.Msg)
+		msg.SetQuestion(dns.Fqdn(name), qtype)
+		resp, _, err := m.client.ExchangeContext(ctx, msg, m.servers[idx])
+		if err != nil {
+			lastErr = err
+			time.Sleep(backoff(attempt))
+			continue
+		}
+		if resp.Rcode == dns.RcodeServerFailure {
+			lastErr = fmt.Errorf("SERVFAIL from %s", m.servers[idx])
+			time.Sleep(backoff(attempt))
+			continue
+		}
+		if resp.Rcode == dns.RcodeNameError {
+			return nil, fmt.Errorf("%s: %w", name, errNXDOMAIN)
+		}
+		return resp, nil
+	}
+	return nil, fmt.Errorf("resolving %s: %w", name, lastErr)
+}
+
+// backoff returns an exponential backoff delay with jitter for the given
+// (zero-indexed) attempt.
+func backoff(attempt int) time.Duration {
+	base := time.Duration(1<<attempt) * 50 * time.Millisecond
+	jitter := time.Duration(rand.Intn(50)) * time.Millisecond
+	return base + jitter
+}
+
+// LoadResolversFile reads one "host" or "host:port" server per line from
+// path, skipping blank lines and comments.
+func LoadResolversFile(path string) ([]string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("reading resolvers file %s: %w", path, err)
+	}
+	defer f.Close()
+
+	var servers []string
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		servers = append(servers, line)
+	}
+	return servers, scanner.Err()
+}
@@ -0,0 +1,175 @@
+package cnfinder
+
+import (
+	"context"
+	"net"
+	"sync/atomic"
+	"testing"
+
+	"github.com/miekg/dns"
+)
+
+// startFakeDNS runs a UDP DNS server on an ephemeral loopback port backed
+// by handler, returning its "host:port" address. It's torn down via
+// t.Cleanup.
+func startFakeDNS(t *testing.T, handler dns.HandlerFunc) string {
+	t.Helper()
+	pc, err := net.ListenPacket("udp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("listening for fake DNS server: %v", err)
+	}
+	mux := dns.NewServeMux()
+	mux.HandleFunc(".", handler)
+	srv := &dns.Server{PacketConn: pc, Handler: mux}
+	go srv.ActivateAndServe()
+	t.Cleanup(func() { srv.Shutdown() })
+	return pc.LocalAddr().String()
+}
+
+// servfailThenAnswer SERVFAILs the first n-1 queries, then answers on the
+// nth (and every one after) by calling rr with the question's name and
+// qtype; rr may return nil for an empty-but-successful response.
+func servfailThenAnswer(n int32, rr func(qname string, qtype uint16) dns.RR) (dns.HandlerFunc, *int32) {
+	var calls int32
+	return func(w dns.ResponseWriter, r *dns.Msg) {
+		got := atomic.AddInt32(&calls, 1)
+		m := new(dns.Msg)
+		m.SetReply(r)
+		if got < n {
+			m.Rcode = dns.RcodeServerFailure
+		} else if rr != nil {
+			if answer := rr(r.Question[0].Name, r.Question[0].Qtype); answer != nil {
+				m.Answer = append(m.Answer, answer)
+			}
+		}
+		w.WriteMsg(m)
+	}, &calls
+}
+
+func alwaysServfail(w dns.ResponseWriter, r *dns.Msg) {
+	m := new(dns.Msg)
+	m.SetReply(r)
+	m.Rcode = dns.RcodeServerFailure
+	w.WriteMsg(m)
+}
+
+func TestLookupCNAMERetriesOnServfail(t *testing.T) {
+	handler, calls := servfailThenAnswer(3, func(qname string, qtype uint16) dns.RR {
+		rr, _ := dns.NewRR(qname + " 60 IN CNAME target.example.net.")
+		return rr
+	})
+	addr := startFakeDNS(t, handler)
+
+	m, err := NewMultiResolver([]string{addr}, 1000, 3)
+	if err != nil {
+		t.Fatalf("NewMultiResolver: %v", err)
+	}
+	cname, err := m.LookupCNAME(context.Background(), "www.example.com")
+	if err != nil {
+		t.Fatalf("LookupCNAME: %v", err)
+	}
+	if cname != "target.example.net" {
+		t.Errorf("cname = %q, want target.example.net", cname)
+	}
+	if got := atomic.LoadInt32(calls); got != 3 {
+		t.Errorf("server saw %d queries, want 3 (2 SERVFAILs then a retry that succeeds)", got)
+	}
+}
+
+func TestLookupHostRetriesOnServfail(t *testing.T) {
+	handler, calls := servfailThenAnswer(3, func(qname string, qtype uint16) dns.RR {
+		if qtype != dns.TypeA {
+			return nil
+		}
+		rr, _ := dns.NewRR(qname + " 60 IN A 203.0.113.5")
+		return rr
+	})
+	addr := startFakeDNS(t, handler)
+
+	m, err := NewMultiResolver([]string{addr}, 1000, 3)
+	if err != nil {
+		t.Fatalf("NewMultiResolver: %v", err)
+	}
+	ips, err := m.LookupHost(context.Background(), "edge.example.com")
+	if err != nil {
+		t.Fatalf("LookupHost: %v", err)
+	}
+	if len(ips) != 1 || ips[0] != "203.0.113.5" {
+		t.Errorf("ips = %v, want [203.0.113.5]", ips)
+	}
+	// 3 calls to resolve the A record (2 SERVFAILs then success), plus 1
+	// more for the AAAA query that follows it.
+	if got := atomic.LoadInt32(calls); got != 4 {
+		t.Errorf("server saw %d queries, want 4", got)
+	}
+}
+
+func TestLookupHostGivesUpAfterRetries(t *testing.T) {
+	addr := startFakeDNS(t, alwaysServfail)
+
+	m, err := NewMultiResolver([]string{addr}, 1000, 2)
+	if err != nil {
+		t.Fatalf("NewMultiResolver: %v", err)
+	}
+	if ips, err := m.LookupHost(context.Background(), "edge.example.com"); err == nil {
+		t.Fatalf("LookupHost = (%v, nil), want a retries-exhausted error", ips)
+	}
+}
+
+func TestLookupHostRotatesPastAFailingServer(t *testing.T) {
+	bad := startFakeDNS(t, alwaysServfail)
+	handler, _ := servfailThenAnswer(1, func(qname string, qtype uint16) dns.RR {
+		if qtype != dns.TypeA {
+			return nil
+		}
+		rr, _ := dns.NewRR(qname + " 60 IN A 203.0.113.9")
+		return rr
+	})
+	good := startFakeDNS(t, handler)
+
+	// With 2 servers and 3 retries (4 attempts total) the round-robin is
+	// guaranteed to reach the good server regardless of which one the
+	// atomic start offset lands on first.
+	m, err := NewMultiResolver([]string{bad, good}, 1000, 3)
+	if err != nil {
+		t.Fatalf("NewMultiResolver: %v", err)
+	}
+	ips, err := m.LookupHost(context.Background(), "edge.example.com")
+	if err != nil {
+		t.Fatalf("LookupHost: %v", err)
+	}
+	if len(ips) != 1 || ips[0] != "203.0.113.9" {
+		t.Errorf("ips = %v, want [203.0.113.9]", ips)
+	}
+}
+
+func TestNewMultiResolverRejectsEmptyServers(t *testing.T) {
+	if _, err := NewMultiResolver(nil, 10, 2); err == nil {
+		t.Fatal("NewMultiResolver(nil, ...) = nil error, want an error")
+	}
+	if _, err := NewMultiResolver([]string{}, 10, 2); err == nil {
+		t.Fatal("NewMultiResolver([]string{}, ...) = nil error, want an error")
+	}
+}
+
+func TestNewMultiResolverAppendsDefaultPort(t *testing.T) {
+	m, err := NewMultiResolver([]string{"1.1.1.1", "8.8.8.8:5353"}, 10, 2)
+	if err != nil {
+		t.Fatalf("NewMultiResolver: %v", err)
+	}
+	want := []string{"1.1.1.1:53", "8.8.8.8:5353"}
+	for i, w := range want {
+		if m.servers[i] != w {
+			t.Errorf("servers[%d] = %q, want %q", i, m.servers[i], w)
+		}
+	}
+}
+
+func TestIsNXDOMAIN(t *testing.T) {
+	if isNXDOMAIN(nil) {
+		t.Error("isNXDOMAIN(nil) = true, want false")
+	}
+	if !isNXDOMAIN(errNXDOMAIN) {
+		t.Error("isNXDOMAIN(errNXDOMAIN) = false, want true")
+	}
+}
@@ -0,0 +1,229 @@
+package cnfinder
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"runtime"
+	"sync"
+	"time"
+)
+
+// Statuses assigned to a Result that are internal to Scanner and must not
+// reach an OutputWriter - they only drive Logger diagnostics. Kept
+// distinct from the Status* constants in output.go, which are the
+// enumerated set callers should persist. Callers that hand every Result
+// from handle to an OutputWriter must first skip anything IsInternalStatus
+// reports true for.
+const (
+	internalStatusNoCNAME  = "no_cname"
+	internalStatusWildcard = "wildcard"
+	internalStatusError    = "error"
+)
+
+// IsInternalStatus reports whether status is one of Scanner's
+// internal-only statuses (see above) rather than one of the Status*
+// constants in output.go.
+func IsInternalStatus(status string) bool {
+	switch status {
+	case internalStatusNoCNAME, internalStatusWildcard, internalStatusError:
+		return true
+	default:
+		return false
+	}
+}
+
+// Logger receives scan diagnostics. Scanner never writes to stdout/stderr
+// itself; callers supply a Logger (or leave it nil for silence) so the
+// CLI, tests, and other embedders can each format output their own way.
+type Logger interface {
+	Debugf(format string, args ...interface{})
+	Infof(format string, args ...interface{})
+	Warnf(format string, args ...interface{})
+	Errorf(format string, args ...interface{})
+}
+
+type noopLogger struct{}
+
+func (noopLogger) Debugf(string, ...interface{}) {}
+func (noopLogger) Infof(string, ...interface{})  {}
+func (noopLogger) Warnf(string, ...interface{})  {}
+func (noopLogger) Errorf(string, ...interface{}) {}
+
+// Options configures a Scanner. Zero values are replaced with sane
+// defaults by NewScanner.
+type Options struct {
+	Resolver        Resolver
+	ResolverName    string // recorded on every Result, e.g. "system" or the upstream server list
+	Workers         int
+	Timeout         time.Duration
+	MaxHops         int
+	WildcardFilter  bool
+	Fingerprints    []Fingerprint
+	TakeoverCheck   bool
+	TakeoverTimeout time.Duration
+	Logger          Logger
+}
+
+// Scanner resolves a batch of subdomains concurrently, optionally
+// filtering wildcard DNS and checking takeover fingerprints.
+type Scanner struct {
+	opts Options
+}
+
+// NewScanner builds a Scanner, filling in defaults for any zero-valued
+// Options field.
+func NewScanner(opts Options) *Scanner {
+	if opts.Resolver == nil {
+		opts.Resolver = SystemResolver{}
+		if opts.ResolverName == "" {
+			opts.ResolverName = "system"
+		}
+	}
+	if opts.Workers <= 0 {
+		opts.Workers = runtime.NumCPU()
+	}
+	if opts.Timeout <= 0 {
+		opts.Timeout = 5 * time.Second
+	}
+	if opts.MaxHops <= 0 {
+		opts.MaxHops = 10
+	}
+	if opts.TakeoverTimeout <= 0 {
+		opts.TakeoverTimeout = 7 * time.Second
+	}
+	if opts.Logger == nil {
+		opts.Logger = noopLogger{}
+	}
+	return &Scanner{opts: opts}
+}
+
+// Scan resolves every entry in subs concurrently and calls handle once
+// per entry as its Result becomes available; handle may be called from
+// multiple goroutines' results but each call itself runs on Scan's own
+// goroutine, so handle doesn't need to be safe for concurrent use by
+// Scan internals. Order of handle calls does not match subs.
+func (s *Scanner) Scan(ctx context.Context, subs []string, handle func(Result)) {
+	baselines := s.wildcardBaselines(ctx, subs)
+
+	jobs := make(chan string)
+	results := make(chan Result)
+	var wg sync.WaitGroup
+
+	for i := 0; i < s.opts.Workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for sub := range jobs {
+				results <- s.resolveOne(ctx, sub, baselines)
+			}
+		}()
+	}
+
+	go func() {
+		for _, sub := range subs {
+			jobs <- sub
+		}
+		close(jobs)
+	}()
+
+	go func() {
+		wg.Wait()
+		close(results)
+	}()
+
+	for res := range results {
+		handle(res)
+	}
+}
+
+// wildcardBaselines resolves one random-label probe per unique apex
+// domain in subs, so Scan can cheaply compare each real result against
+// it later.
+func (s *Scanner) wildcardBaselines(ctx context.Context, subs []string) map[string]Chain {
+	baselines := make(map[string]Chain)
+	if !s.opts.WildcardFilter {
+		return baselines
+	}
+	seen := make(map[string]struct{})
+	for _, sub := range subs {
+		apex := RegistrableDomain(sub)
+		if _, ok := seen[apex]; ok {
+			continue
+		}
+		seen[apex] = struct{}{}
+
+		cctx, cancel := context.WithTimeout(ctx, s.opts.Timeout)
+		baseline, err := WildcardBaseline(cctx, s.opts.Resolver, apex, s.opts.MaxHops)
+		cancel()
+		if err != nil {
+			s.opts.Logger.Debugf("wildcard baseline for %s failed: %v", apex, err)
+			continue
+		}
+		baselines[apex] = baseline
+	}
+	return baselines
+}
+
+// resolveOne resolves a single subdomain's chain, checks it against the
+// wildcard baseline for its apex, and runs a takeover fingerprint probe
+// if configured.
+func (s *Scanner) resolveOne(ctx context.Context, sub string, baselines map[string]Chain) Result {
+	start := time.Now()
+	cctx, cancel := context.WithTimeout(ctx, s.opts.Timeout)
+	chain, err := ResolveChain(cctx, s.opts.Resolver, sub, s.opts.MaxHops)
+	cancel()
+	elapsed := time.Since(start).Milliseconds()
+
+	if err != nil {
+		status := internalStatusError
+		if isNXDOMAIN(err) {
+			status = StatusNXDomain
+		} else if dnsErr, ok := err.(*net.DNSError); ok && dnsErr.IsTimeout {
+			status = StatusTimeout
+		}
+		s.opts.Logger.Warnf("error resolving %s: %v", sub, err)
+		return Result{Input: sub, Status: status, ElapsedMS: elapsed, Resolver: s.opts.ResolverName}
+	}
+
+	if len(chain.Hops) == 0 {
+		s.opts.Logger.Debugf("no CNAME record for %s", sub)
+		return Result{Input: sub, Status: internalStatusNoCNAME, ElapsedMS: elapsed, Resolver: s.opts.ResolverName}
+	}
+	cname := chain.Hops[len(chain.Hops)-1]
+
+	if baseline, ok := baselines[RegistrableDomain(sub)]; ok && MatchesWildcard(chain, baseline) {
+		s.opts.Logger.Debugf("%s > %s matches wildcard DNS, skipping", sub, cname)
+		return Result{Input: sub, Chain: chain.Hops, Status: internalStatusWildcard, ElapsedMS: elapsed, Resolver: s.opts.ResolverName}
+	}
+
+	res := Result{
+		Input:      sub,
+		Chain:      chain.Hops,
+		TerminalIP: chain.TerminalIPs,
+		Status:     StatusOK,
+		ElapsedMS:  elapsed,
+		Resolver:   s.opts.ResolverName,
+	}
+	if chain.Dangling {
+		res.Status = StatusDangling
+		s.opts.Logger.Warnf("%s > %s is DANGLING (no terminal A/AAAA record)", sub, cname)
+	}
+
+	if s.opts.TakeoverCheck {
+		if fp := MatchFingerprint(cname, s.opts.Fingerprints); fp != nil {
+			finding := CheckTakeover(sub, fp, s.opts.TakeoverTimeout)
+			res.Fingerprint = fmt.Sprintf("%s:%s", finding.Status, finding.Service)
+			res.HTTPStatus = finding.HTTPStatus
+			switch finding.Status {
+			case StatusVulnerable:
+				res.Status = StatusTakeover
+				s.opts.Logger.Errorf("%s > %s VULNERABLE (%s)", sub, cname, finding.Service)
+			case StatusNeedsManualReview:
+				s.opts.Logger.Infof("%s > %s needs manual review (%s)", sub, cname, finding.Service)
+			}
+		}
+	}
+
+	return res
+}
@@ -0,0 +1,44 @@
+package cnfinder
+
+import (
+	"context"
+	"testing"
+)
+
+func TestIsInternalStatus(t *testing.T) {
+	for _, s := range []string{internalStatusNoCNAME, internalStatusWildcard, internalStatusError} {
+		if !IsInternalStatus(s) {
+			t.Errorf("IsInternalStatus(%q) = false, want true", s)
+		}
+	}
+	for _, s := range []string{StatusOK, StatusNXDomain, StatusTimeout, StatusDangling, StatusTakeover} {
+		if IsInternalStatus(s) {
+			t.Errorf("IsInternalStatus(%q) = true, want false", s)
+		}
+	}
+}
+
+func TestScannerScanStatuses(t *testing.T) {
+	res := &fakeResolver{
+		cnames: map[string]string{"has-cname.example.com": "edge.example.net"},
+		nx:     map[string]bool{"missing.example.com": true},
+		hosts:  map[string][]string{"edge.example.net": {"203.0.113.1"}},
+	}
+	s := NewScanner(Options{Resolver: res})
+
+	got := make(map[string]string)
+	s.Scan(context.Background(), []string{"has-cname.example.com", "no-cname.example.com", "missing.example.com"}, func(r Result) {
+		got[r.Input] = r.Status
+	})
+
+	want := map[string]string{
+		"has-cname.example.com": StatusOK,
+		"no-cname.example.com":  internalStatusNoCNAME,
+		"missing.example.com":   StatusNXDomain,
+	}
+	for input, wantStatus := range want {
+		if got[input] != wantStatus {
+			t.Errorf("status for %s = %q, want %q", input, got[input], wantStatus)
+		}
+	}
+}
@@ -0,0 +1,81 @@
+package cnfinder
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// Takeover verdicts emitted for a subdomain whose CNAME matched a known
+// fingerprint.
+const (
+	StatusVulnerable        = "vulnerable"
+	StatusNotVulnerable     = "not-vulnerable"
+	StatusNeedsManualReview = "needs-manual-review"
+)
+
+// TakeoverFinding is the result of probing a subdomain whose CNAME
+// matched a fingerprint.
+type TakeoverFinding struct {
+	Service    string
+	Status     string
+	HTTPStatus int
+}
+
+// CheckTakeover performs an HTTP GET against sub (trying https then http)
+// and compares the response against fp's body signatures and status
+// codes to decide whether the dangling CNAME is actually exploitable.
+func CheckTakeover(sub string, fp *Fingerprint, timeout time.Duration) TakeoverFinding {
+	client := &http.Client{Timeout: timeout}
+
+	for _, scheme := range []string{"https", "http"} {
+		ctx, cancel := context.WithTimeout(context.Background(), timeout)
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, scheme+"://"+sub, nil)
+		if err != nil {
+			cancel()
+			continue
+		}
+		resp, err := client.Do(req)
+		if err != nil {
+			cancel()
+			continue
+		}
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+		resp.Body.Close()
+		cancel()
+
+		status := classify(fp, resp.StatusCode, string(body))
+		return TakeoverFinding{Service: fp.Service, Status: status, HTTPStatus: resp.StatusCode}
+	}
+
+	// Neither scheme answered; we can't confirm, so leave it for a human.
+	return TakeoverFinding{Service: fp.Service, Status: StatusNeedsManualReview}
+}
+
+// classify decides a verdict from the HTTP response against fp's
+// expected signatures.
+func classify(fp *Fingerprint, httpStatus int, body string) string {
+	statusMatches := len(fp.HTTPStatuses) == 0
+	for _, s := range fp.HTTPStatuses {
+		if s == httpStatus {
+			statusMatches = true
+			break
+		}
+	}
+
+	for _, sig := range fp.BodySignatures {
+		if strings.Contains(body, sig) {
+			if statusMatches {
+				return StatusVulnerable
+			}
+			return StatusNeedsManualReview
+		}
+	}
+
+	if statusMatches {
+		return StatusNeedsManualReview
+	}
+	return StatusNotVulnerable
+}
@@ -0,0 +1,9 @@
+package cnfinder
+
+import "strings"
+
+// normalize strips a trailing FQDN dot and surrounding whitespace so
+// names coming back from different resolvers compare equal.
+func normalize(name string) string {
+	return strings.TrimSuffix(strings.TrimSpace(name), ".")
+}